@@ -3,13 +3,17 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Ruscigno/stock-screener/internal/config"
+	"github.com/Ruscigno/stock-screener/internal/indicators"
 	"github.com/Ruscigno/stock-screener/internal/match"
 	"github.com/Ruscigno/stock-screener/internal/screener"
 	"github.com/Ruscigno/stock-screener/internal/timeframe"
@@ -18,6 +22,7 @@ import (
 // ScreenRunner is the screener dependency (real or fake).
 type ScreenRunner interface {
 	Screen(ctx context.Context, req screener.Request) (screener.Result, error)
+	Series(ctx context.Context, req screener.SeriesRequest) ([]screener.SeriesPoint, error)
 }
 
 // Pinger checks backing-store health.
@@ -25,24 +30,55 @@ type Pinger interface {
 	Ping(ctx context.Context) error
 }
 
+// defaultCacheMaxEntries bounds the result cache when
+// server.cache_max_entries is unset.
+const defaultCacheMaxEntries = 256
+
+// BuildInfo identifies the running binary; served by /version.
+type BuildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Dirty   bool   `json:"dirty"`
+}
+
 type Server struct {
-	scr ScreenRunner
-	db  Pinger
-	cfg *config.Config
+	scr     ScreenRunner
+	db      Pinger
+	cfg     *config.Config
+	symbols symbolIndex
+	build   BuildInfo
+	cache   *resultCache[screener.Result] // nil when server.cache_ttl is 0
 }
 
 func NewServer(scr ScreenRunner, db Pinger, cfg *config.Config) *Server {
-	return &Server{scr: scr, db: db, cfg: cfg}
+	s := &Server{scr: scr, db: db, cfg: cfg, symbols: newSymbolIndex(cfg.Stocks)}
+	if ttl := time.Duration(cfg.Server.CacheTTL); ttl > 0 {
+		max := cfg.Server.CacheMaxEntries
+		if max <= 0 {
+			max = defaultCacheMaxEntries
+		}
+		s.cache = newResultCache[screener.Result](ttl, max)
+	}
+	return s
 }
 
+// SetBuildInfo sets what /version reports.
+func (s *Server) SetBuildInfo(b BuildInfo) { s.build = b }
+
 func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/screen", s.handleScreen)
 	mux.HandleFunc("/matches", s.handleMatches)
+	mux.HandleFunc("/indicators", s.handleIndicators)
 	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/version", s.handleVersion)
 	return mux
 }
 
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.build)
+}
+
 func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
@@ -69,6 +105,17 @@ func (s *Server) parseRequest(r *http.Request) (screener.Request, *reqError) {
 		Match:      orDefault(r.URL.Query().Get("match"), s.cfg.Screening.Match),
 		Indicators: csvOrDefault(r.URL.Query().Get("indicators"), screener.AllIndicators),
 	}
+	// Resolve symbols to their configured spelling first (aapl -> AAPL), so
+	// the dedupe below also folds differently-cased repeats.
+	resolved := make([]string, 0, len(req.Symbols))
+	for _, in := range req.Symbols {
+		sym, err := s.symbols.resolve(in)
+		if err != nil {
+			return req, &reqError{http.StatusBadRequest, err.Error()}
+		}
+		resolved = append(resolved, sym)
+	}
+	req.Symbols = resolved
 	// Dedupe so a repeated symbol/timeframe isn't evaluated (or listed) twice;
 	// /matches is one-entry-per-stock by definition. (Indicators keep their
 	// reject-on-duplicate behavior below.)
@@ -79,15 +126,6 @@ func (s *Server) parseRequest(r *http.Request) (screener.Request, *reqError) {
 			return req, &reqError{http.StatusBadRequest, "unknown timeframe: " + tf}
 		}
 	}
-	allowed := make(map[string]bool, len(s.cfg.Stocks))
-	for _, sym := range s.cfg.Stocks {
-		allowed[sym] = true
-	}
-	for _, sym := range req.Symbols {
-		if !allowed[sym] {
-			return req, &reqError{http.StatusBadRequest, "unknown symbol: " + sym}
-		}
-	}
 	if !match.Valid(req.Match) {
 		return req, &reqError{http.StatusBadRequest, "invalid match mode: " + req.Match}
 	}
@@ -106,7 +144,12 @@ func (s *Server) run(w http.ResponseWriter, r *http.Request, name string, encode
 		http.Error(w, rerr.msg, rerr.status)
 		return
 	}
-	result, err := s.scr.Screen(r.Context(), req)
+	result, err := s.screen(r.Context(), req)
+	if err != nil && r.Context().Err() != nil {
+		// The client hung up while waiting on a (shared) run; nobody is left
+		// to answer and it isn't a server failure.
+		return
+	}
 	if err != nil {
 		log.Printf("%s failed: %v", name, err)
 		http.Error(w, "internal error", http.StatusInternalServerError)
@@ -115,6 +158,20 @@ func (s *Server) run(w http.ResponseWriter, r *http.Request, name string, encode
 	writeJSON(w, encode(result, req))
 }
 
+// screen runs the screener, through the result cache when one is configured.
+// The key is the parsed (defaulted, deduped) request rather than the raw query,
+// so equivalent queries — and /screen and /matches for the same criteria —
+// share one entry.
+func (s *Server) screen(ctx context.Context, req screener.Request) (screener.Result, error) {
+	if s.cache == nil {
+		return s.scr.Screen(ctx, req)
+	}
+	key := fmt.Sprintf("%q", []any{req.Symbols, req.Timeframes, req.Match, req.Indicators})
+	return s.cache.do(ctx, key, func(ctx context.Context) (screener.Result, error) {
+		return s.scr.Screen(ctx, req)
+	})
+}
+
 func (s *Server) handleScreen(w http.ResponseWriter, r *http.Request) {
 	s.run(w, r, "screen", func(res screener.Result, req screener.Request) any { return toDTO(res, req) })
 }
@@ -123,6 +180,77 @@ func (s *Server) handleMatches(w http.ResponseWriter, r *http.Request) {
 	s.run(w, r, "matches", func(res screener.Result, req screener.Request) any { return toMatchesDTO(res, req) })
 }
 
+// Bounds for /indicators so one request cannot load unbounded history.
+const (
+	maxSeriesPeriod = 1000
+	defaultPoints   = 100
+	maxPoints       = 1000
+)
+
+// parseSeriesRequest builds a screener.SeriesRequest from the /indicators query
+// string. symbol, type and period are required; timeframe defaults to 1d and
+// limit (trailing values returned) to defaultPoints.
+func (s *Server) parseSeriesRequest(r *http.Request) (screener.SeriesRequest, *reqError) {
+	q := r.URL.Query()
+	req := screener.SeriesRequest{
+		Symbol:    strings.TrimSpace(q.Get("symbol")),
+		Timeframe: orDefault(q.Get("timeframe"), "1d"),
+		Kind:      strings.ToLower(strings.TrimSpace(q.Get("type"))),
+		Points:    defaultPoints,
+	}
+	if req.Symbol == "" {
+		return req, &reqError{http.StatusBadRequest, "symbol is required"}
+	}
+	sym, err := s.symbols.resolve(req.Symbol)
+	if err != nil {
+		return req, &reqError{http.StatusBadRequest, err.Error()}
+	}
+	req.Symbol = sym
+	if _, ok := timeframe.Get(req.Timeframe); !ok {
+		return req, &reqError{http.StatusBadRequest, "unknown timeframe: " + req.Timeframe}
+	}
+	if indicators.MinBars(req.Kind, 1) == 0 {
+		return req, &reqError{http.StatusBadRequest,
+			fmt.Sprintf("unknown type: %q (want %s)", q.Get("type"), strings.Join(indicators.Kinds, "|"))}
+	}
+	period, err := strconv.Atoi(q.Get("period"))
+	if err != nil || period < 1 || period > maxSeriesPeriod {
+		return req, &reqError{http.StatusBadRequest, fmt.Sprintf("period must be an integer in [1, %d]", maxSeriesPeriod)}
+	}
+	req.Period = period
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPoints {
+			return req, &reqError{http.StatusBadRequest, fmt.Sprintf("limit must be an integer in [1, %d]", maxPoints)}
+		}
+		req.Points = n
+	}
+	return req, nil
+}
+
+// handleIndicators serves one indicator's recent values for a (symbol,
+// timeframe). A period the stored history cannot satisfy is a 400 carrying the
+// screener's insufficient_data message; other failures are a generic 500.
+func (s *Server) handleIndicators(w http.ResponseWriter, r *http.Request) {
+	req, rerr := s.parseSeriesRequest(r)
+	if rerr != nil {
+		http.Error(w, rerr.msg, rerr.status)
+		return
+	}
+	points, err := s.scr.Series(r.Context(), req)
+	var ide *screener.InsufficientDataError
+	if errors.As(err, &ide) {
+		http.Error(w, ide.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("indicators failed: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, toSeriesDTO(points, req))
+}
+
 // validateIndicators rejects unknown or duplicate indicator names, bounding the
 // per-request work (consistent with the symbols/timeframes validation).
 func validateIndicators(inds []string) error {
@@ -234,7 +362,7 @@ type responseDTO struct {
 
 func toDTO(res screener.Result, req screener.Request) responseDTO {
 	var out responseDTO
-	out.AsOf = time.Now().UTC()
+	out.AsOf = asOf(res)
 	out.Criteria.Match = req.Match
 	out.Criteria.Symbols = len(req.Symbols)
 	out.Criteria.Timeframes = req.Timeframes
@@ -260,6 +388,15 @@ func toDTO(res screener.Result, req screener.Request) responseDTO {
 	return out
 }
 
+// asOf is when res was computed, which for a cached result is up to
+// server.cache_ttl ago. Runners that leave it unset are stamped now.
+func asOf(res screener.Result) time.Time {
+	if res.AsOf.IsZero() {
+		return time.Now().UTC()
+	}
+	return res.AsOf
+}
+
 type matchesResponseDTO struct {
 	AsOf     time.Time `json:"as_of"`
 	Criteria struct {
@@ -273,7 +410,7 @@ type matchesResponseDTO struct {
 
 func toMatchesDTO(res screener.Result, req screener.Request) matchesResponseDTO {
 	var out matchesResponseDTO
-	out.AsOf = time.Now().UTC()
+	out.AsOf = asOf(res)
 	out.Criteria.Match = req.Match
 	out.Criteria.Symbols = len(req.Symbols)
 	out.Criteria.Timeframes = req.Timeframes
@@ -285,6 +422,31 @@ func toMatchesDTO(res screener.Result, req screener.Request) matchesResponseDTO
 	return out
 }
 
+type seriesPointDTO struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+type seriesResponseDTO struct {
+	AsOf      time.Time        `json:"as_of"`
+	Symbol    string           `json:"symbol"`
+	Timeframe string           `json:"timeframe"`
+	Type      string           `json:"type"`
+	Period    int              `json:"period"`
+	Values    []seriesPointDTO `json:"values"`
+}
+
+func toSeriesDTO(points []screener.SeriesPoint, req screener.SeriesRequest) seriesResponseDTO {
+	out := seriesResponseDTO{
+		AsOf: time.Now().UTC(), Symbol: req.Symbol, Timeframe: req.Timeframe,
+		Type: req.Kind, Period: req.Period,
+		Values: make([]seriesPointDTO, 0, len(points)),
+	}
+	for _, p := range points {
+		out.Values = append(out.Values, seriesPointDTO(p))
+	}
+	return out
+}
+
 func pivotsToDTO(in []screener.PivotPoint) []pivotDTO {
 	out := make([]pivotDTO, 0, len(in))
 	for _, p := range in {
@@ -298,9 +460,11 @@ func writeJSON(w http.ResponseWriter, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
+// csvOrDefault splits a comma-separated param, or returns a copy of def when
+// it is empty — never def itself, which is config shared by every request.
 func csvOrDefault(s string, def []string) []string {
 	if strings.TrimSpace(s) == "" {
-		return def
+		return slices.Clone(def)
 	}
 	parts := strings.Split(s, ",")
 	out := make([]string, 0, len(parts))
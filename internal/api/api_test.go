@@ -1,11 +1,16 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,12 +18,22 @@ import (
 	"github.com/Ruscigno/stock-screener/internal/screener"
 )
 
-type fakeScreener struct{ res screener.Result }
+type fakeScreener struct {
+	res    screener.Result
+	series []screener.SeriesPoint
+	serErr error
+	serReq screener.SeriesRequest
+}
 
 func (f *fakeScreener) Screen(context.Context, screener.Request) (screener.Result, error) {
 	return f.res, nil
 }
 
+func (f *fakeScreener) Series(_ context.Context, req screener.SeriesRequest) ([]screener.SeriesPoint, error) {
+	f.serReq = req
+	return f.series, f.serErr
+}
+
 type fakePinger struct{ err error }
 
 func (f *fakePinger) Ping(context.Context) error { return f.err }
@@ -211,6 +226,10 @@ func (erroringScreener) Screen(context.Context, screener.Request) (screener.Resu
 	return screener.Result{}, errBoom
 }
 
+func (erroringScreener) Series(context.Context, screener.SeriesRequest) ([]screener.SeriesPoint, error) {
+	return nil, errBoom
+}
+
 var errBoom = boomErr("internal detail that must not leak")
 
 type boomErr string
@@ -356,3 +375,221 @@ func TestMatchesDedupesSymbols(t *testing.T) {
 		t.Errorf("criteria.symbols = %d, want 1 (deduped)", body.Criteria.Symbols)
 	}
 }
+
+func TestIndicatorsEndpoint(t *testing.T) {
+	t0 := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	fake := &fakeScreener{series: []screener.SeriesPoint{{Time: t0, Value: 101.5}, {Time: t0.AddDate(0, 0, 1), Value: 102}}}
+	srv := NewServer(fake, &fakePinger{}, testCfg())
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/indicators?symbol=AAPL&type=SMA&period=14&limit=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (%s)", rec.Code, rec.Body.String())
+	}
+	want := screener.SeriesRequest{Symbol: "AAPL", Timeframe: "1d", Kind: "sma", Period: 14, Points: 2}
+	if fake.serReq != want {
+		t.Errorf("series request = %+v, want %+v", fake.serReq, want)
+	}
+	var body struct {
+		Symbol string `json:"symbol"`
+		Type   string `json:"type"`
+		Period int    `json:"period"`
+		Values []struct {
+			Time  string  `json:"time"`
+			Value float64 `json:"value"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Symbol != "AAPL" || body.Type != "sma" || body.Period != 14 {
+		t.Errorf("meta = %+v", body)
+	}
+	if len(body.Values) != 2 || body.Values[1].Value != 102 || body.Values[0].Time == "" {
+		t.Errorf("values = %+v", body.Values)
+	}
+}
+
+func TestIndicatorsValidatesParams(t *testing.T) {
+	srv := NewServer(&fakeScreener{}, &fakePinger{}, testCfg())
+	for _, q := range []string{
+		"type=sma&period=14",             // missing symbol
+		"symbol=NOPE&type=sma&period=14", // unknown symbol
+		"symbol=AAPL&timeframe=7m&type=sma&period=14",
+		"symbol=AAPL&type=macd&period=14",
+		"symbol=AAPL&type=sma", // missing period
+		"symbol=AAPL&type=sma&period=0",
+		"symbol=AAPL&type=sma&period=100000",
+		"symbol=AAPL&type=sma&period=14&limit=0",
+	} {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/indicators?"+q, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", q, rec.Code)
+		}
+	}
+}
+
+func TestIndicatorsInsufficientDataIs400(t *testing.T) {
+	fake := &fakeScreener{serErr: &screener.InsufficientDataError{Indicator: "sma(200)", Need: 200, Have: 50}}
+	srv := NewServer(fake, &fakePinger{}, testCfg())
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/indicators?symbol=AAPL&type=sma&period=200", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "needs 200 bars, have 50") {
+		t.Errorf("body = %q, want insufficient_data detail", rec.Body.String())
+	}
+}
+
+func TestIndicatorsInternalErrorIsGeneric(t *testing.T) {
+	srv := NewServer(erroringScreener{}, &fakePinger{}, testCfg())
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/indicators?symbol=AAPL&type=rsi&period=14", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "internal detail") {
+		t.Errorf("response leaked internal error: %q", rec.Body.String())
+	}
+}
+
+// countingScreener counts Screen calls and blocks each until release closes.
+type countingScreener struct {
+	fakeScreener
+	calls   int32
+	release chan struct{}
+}
+
+func (c *countingScreener) Screen(context.Context, screener.Request) (screener.Result, error) {
+	atomic.AddInt32(&c.calls, 1)
+	<-c.release
+	return c.res, nil
+}
+
+func TestScreenCacheCollapsesConcurrentGETs(t *testing.T) {
+	cfg := testCfg()
+	cfg.Server.CacheTTL = config.Duration(time.Minute)
+	scr := &countingScreener{release: make(chan struct{})}
+	h := NewServer(scr, &fakePinger{}, cfg).Handler()
+
+	const n = 8
+	var wg sync.WaitGroup
+	codes := make([]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Alternate endpoints: /screen and /matches share the screener run.
+			path := "/screen"
+			if i%2 == 1 {
+				path = "/matches"
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?symbols=AAPL", nil))
+			codes[i] = rec.Code
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(scr.release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&scr.calls); got != 1 {
+		t.Errorf("screener calls = %d, want 1", got)
+	}
+	for i, c := range codes {
+		if c != http.StatusOK {
+			t.Errorf("request %d: status = %d, want 200", i, c)
+		}
+	}
+
+	// A different query is a different key.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/screen?match=all", nil))
+	if got := atomic.LoadInt32(&scr.calls); got != 2 {
+		t.Errorf("screener calls after distinct query = %d, want 2", got)
+	}
+}
+
+func TestScreenCacheDisabledByDefault(t *testing.T) {
+	scr := &countingScreener{release: make(chan struct{})}
+	close(scr.release)
+	h := NewServer(scr, &fakePinger{}, testCfg()).Handler()
+	for i := 0; i < 2; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/screen", nil))
+	}
+	if got := atomic.LoadInt32(&scr.calls); got != 2 {
+		t.Errorf("screener calls = %d, want 2 (no cache without cache_ttl)", got)
+	}
+}
+
+func TestVersionEndpoint(t *testing.T) {
+	srv := NewServer(&fakeScreener{}, &fakePinger{}, testCfg())
+	srv.SetBuildInfo(BuildInfo{Version: "v1.2.0", Commit: "abc1234", Dirty: true})
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var got BuildInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got != (BuildInfo{Version: "v1.2.0", Commit: "abc1234", Dirty: true}) {
+		t.Errorf("version = %+v", got)
+	}
+}
+
+// stampingScreener returns a result stamped with the time of each call.
+type stampingScreener struct{ fakeScreener }
+
+func (s *stampingScreener) Screen(context.Context, screener.Request) (screener.Result, error) {
+	return screener.Result{AsOf: time.Now().UTC()}, nil
+}
+
+func TestCachedResponseKeepsComputedAsOf(t *testing.T) {
+	cfg := testCfg()
+	cfg.Server.CacheTTL = config.Duration(time.Minute)
+	h := NewServer(&stampingScreener{}, &fakePinger{}, cfg).Handler()
+	asOf := func(path string) time.Time {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var body struct {
+			AsOf time.Time `json:"as_of"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode: %v", path, err)
+		}
+		return body.AsOf
+	}
+	first := asOf("/screen")
+	time.Sleep(10 * time.Millisecond)
+	for _, path := range []string{"/screen", "/matches"} {
+		if got := asOf(path); !got.Equal(first) {
+			t.Errorf("%s from cache: as_of = %v, want the computed %v", path, got, first)
+		}
+	}
+}
+
+func TestClientDisconnectWhileWaitingIsNotAnError(t *testing.T) {
+	cfg := testCfg()
+	cfg.Server.CacheTTL = config.Duration(time.Minute)
+	scr := &countingScreener{release: make(chan struct{})}
+	defer close(scr.release)
+	h := NewServer(scr, &fakePinger{}, cfg).Handler()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/screen", nil).WithContext(ctx))
+	if rec.Code == http.StatusInternalServerError || rec.Body.Len() != 0 {
+		t.Errorf("status = %d, body = %q; want nothing written for a gone client", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(logs.String(), "failed") {
+		t.Errorf("log = %q, want no error logged for a client disconnect", logs.String())
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// resultCache memoizes results for a short TTL and collapses concurrent calls
+// for the same key into one (singleflight), so dashboards polling the same
+// query share a single screener run instead of each loading every bar. It
+// holds at most maxEntries keys, evicting the least recently used beyond that,
+// so many distinct queries cannot grow it without bound.
+type resultCache[V any] struct {
+	ttl        time.Duration
+	maxEntries int // <= 0 = unbounded
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry[V]
+	lru     *list.List // keys, most recently used at the front
+}
+
+type cacheEntry[V any] struct {
+	done    chan struct{} // closed once the fields below are set
+	ready   bool          // guarded by resultCache.mu
+	val     V
+	err     error
+	expires time.Time
+	elem    *list.Element // this entry's key in resultCache.lru
+}
+
+func newResultCache[V any](ttl time.Duration, maxEntries int) *resultCache[V] {
+	return &resultCache[V]{
+		ttl: ttl, maxEntries: maxEntries, now: time.Now,
+		entries: map[string]*cacheEntry[V]{}, lru: list.New(),
+	}
+}
+
+// do returns the cached value for key, joins an in-flight call for it, or
+// starts fn. fn runs detached from ctx's cancellation so one caller hanging up
+// does not fail every request sharing the flight (the store's query timeout
+// still bounds it); each caller stops waiting when its own ctx is done. Errors
+// are returned to everyone waiting but never cached.
+func (c *resultCache[V]) do(ctx context.Context, key string, fn func(context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	e := c.entries[key]
+	if e != nil && e.ready && !c.now().Before(e.expires) {
+		c.removeLocked(key, e)
+		e = nil
+	}
+	if e == nil {
+		c.pruneLocked()
+		e = &cacheEntry[V]{done: make(chan struct{})}
+		e.elem = c.lru.PushFront(key)
+		c.entries[key] = e
+		c.evictLocked()
+		go c.fill(context.WithoutCancel(ctx), key, e, fn)
+	} else {
+		c.lru.MoveToFront(e.elem)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-e.done:
+		return e.val, e.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (c *resultCache[V]) fill(ctx context.Context, key string, e *cacheEntry[V], fn func(context.Context) (V, error)) {
+	val, err := fn(ctx)
+	c.mu.Lock()
+	e.val, e.err, e.expires, e.ready = val, err, c.now().Add(c.ttl), true
+	if err != nil && c.entries[key] == e {
+		c.removeLocked(key, e)
+	}
+	c.mu.Unlock()
+	close(e.done)
+}
+
+// pruneLocked drops expired entries so they don't occupy slots that live ones
+// could use. Caller holds c.mu.
+func (c *resultCache[V]) pruneLocked() {
+	now := c.now()
+	for k, e := range c.entries {
+		if e.ready && !now.Before(e.expires) {
+			c.removeLocked(k, e)
+		}
+	}
+}
+
+// evictLocked drops least recently used keys beyond maxEntries. An evicted
+// in-flight call still completes for the callers already waiting on it.
+// Caller holds c.mu.
+func (c *resultCache[V]) evictLocked() {
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		k := c.lru.Back().Value.(string)
+		c.removeLocked(k, c.entries[k])
+	}
+}
+
+func (c *resultCache[V]) removeLocked(key string, e *cacheEntry[V]) {
+	delete(c.entries, key)
+	c.lru.Remove(e.elem)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResultCacheCollapsesConcurrentCalls(t *testing.T) {
+	c := newResultCache[int](time.Minute, 0)
+	var calls int32
+	release := make(chan struct{})
+	fn := func(context.Context) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return 42, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	got := make([]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i], _ = c.do(context.Background(), "k", fn)
+		}(i)
+	}
+	// Let every caller join the flight before it completes.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("fn calls = %d, want 1", calls)
+	}
+	for i, v := range got {
+		if v != 42 {
+			t.Errorf("caller %d got %d, want 42", i, v)
+		}
+	}
+}
+
+func TestResultCacheExpires(t *testing.T) {
+	c := newResultCache[int](time.Minute, 0)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	var calls int
+	fn := func(context.Context) (int, error) { calls++; return calls, nil }
+
+	if v, _ := c.do(context.Background(), "k", fn); v != 1 {
+		t.Fatalf("first = %d, want 1", v)
+	}
+	now = now.Add(59 * time.Second)
+	if v, _ := c.do(context.Background(), "k", fn); v != 1 {
+		t.Errorf("within ttl = %d, want cached 1", v)
+	}
+	now = now.Add(time.Second)
+	if v, _ := c.do(context.Background(), "k", fn); v != 2 {
+		t.Errorf("after ttl = %d, want fresh 2", v)
+	}
+}
+
+func TestResultCacheDoesNotCacheErrors(t *testing.T) {
+	c := newResultCache[int](time.Minute, 0)
+	var calls int
+	fn := func(context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("boom")
+		}
+		return 7, nil
+	}
+	if _, err := c.do(context.Background(), "k", fn); err == nil {
+		t.Fatal("first call: want error")
+	}
+	if v, err := c.do(context.Background(), "k", fn); err != nil || v != 7 {
+		t.Errorf("retry = %d, %v; want 7, nil (error not cached)", v, err)
+	}
+}
+
+func TestResultCacheCallerCancelDoesNotFailFlight(t *testing.T) {
+	c := newResultCache[int](time.Minute, 0)
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		<-release
+		return 1, ctx.Err()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := c.do(ctx, "k", fn)
+		leaderDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-leaderDone; !errors.Is(err, context.Canceled) {
+		t.Fatalf("leader err = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if v, err := c.do(context.Background(), "k", fn); err != nil || v != 1 {
+		t.Errorf("follower = %d, %v; want 1, nil (flight unaffected by leader cancel)", v, err)
+	}
+}
+
+func TestResultCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newResultCache[string](time.Minute, 2)
+	calls := map[string]int{}
+	get := func(key string) {
+		_, _ = c.do(context.Background(), key, func(context.Context) (string, error) {
+			calls[key]++
+			return key, nil
+		})
+	}
+	get("a")
+	get("b")
+	get("a") // hit: a becomes most recently used, b is now the LRU
+	get("c") // over the cap of 2: evicts b
+
+	if len(c.entries) != 2 || c.lru.Len() != 2 {
+		t.Fatalf("entries = %d, lru = %d, want 2/2", len(c.entries), c.lru.Len())
+	}
+	get("a")
+	get("b")
+	if calls["a"] != 1 {
+		t.Errorf("a computed %d times, want 1 (kept as recently used)", calls["a"])
+	}
+	if calls["b"] != 2 {
+		t.Errorf("b computed %d times, want 2 (evicted as LRU)", calls["b"])
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSuggestDistance is the largest edit distance at which an unknown symbol
+// still gets a "did you mean" suggestion.
+const maxSuggestDistance = 2
+
+// normalizeSymbol canonicalizes user input to Yahoo's ticker form: trimmed,
+// upper-case, with a "/" share-class separator written as "-" (brk/b ->
+// BRK-B). "." is kept as is because Yahoo uses it for exchange suffixes
+// (SHOP.TO).
+func normalizeSymbol(s string) string {
+	return strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(s)), "/", "-")
+}
+
+// symbolIndex maps normalized symbols to their configured spelling.
+type symbolIndex map[string]string
+
+func newSymbolIndex(configured []string) symbolIndex {
+	idx := make(symbolIndex, len(configured))
+	for _, sym := range configured {
+		idx[normalizeSymbol(sym)] = sym
+	}
+	return idx
+}
+
+// resolve returns the configured symbol that in names. An unknown symbol is
+// an error that suggests the closest configured one, when any is close.
+func (idx symbolIndex) resolve(in string) (string, error) {
+	norm := normalizeSymbol(in)
+	if sym, ok := idx[norm]; ok {
+		return sym, nil
+	}
+	best, bestDist := "", maxSuggestDistance+1
+	for n, sym := range idx {
+		// Ties resolve alphabetically so the suggestion is deterministic.
+		if d := editDistance(norm, n); d < bestDist || (d == bestDist && sym < best) {
+			best, bestDist = sym, d
+		}
+	}
+	if best != "" {
+		return "", fmt.Errorf("unknown symbol: %s (did you mean %s?)", in, best)
+	}
+	return "", fmt.Errorf("unknown symbol: %s", in)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
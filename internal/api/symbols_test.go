@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSymbolIndexResolve(t *testing.T) {
+	idx := newSymbolIndex([]string{"AAPL", "MSFT", "BRK-B", "SHOP.TO"})
+	for in, want := range map[string]string{
+		"AAPL":    "AAPL",
+		"aapl":    "AAPL",
+		" Aapl\t": "AAPL",
+		"brk/b":   "BRK-B",
+		"BRK-B":   "BRK-B",
+		"shop.to": "SHOP.TO",
+		" msft ":  "MSFT",
+	} {
+		got, err := idx.resolve(in)
+		if err != nil || got != want {
+			t.Errorf("resolve(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestSymbolIndexSuggestsClosest(t *testing.T) {
+	idx := newSymbolIndex([]string{"AAPL", "MSFT"})
+	cases := map[string]string{
+		"APPL":  "unknown symbol: APPL (did you mean AAPL?)",
+		"msf":   "unknown symbol: msf (did you mean MSFT?)",
+		"NVDA":  "unknown symbol: NVDA",
+		"BRK.B": "unknown symbol: BRK.B",
+	}
+	for in, want := range cases {
+		_, err := idx.resolve(in)
+		if err == nil || err.Error() != want {
+			t.Errorf("resolve(%q) err = %v, want %q", in, err, want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0}, {"AAPL", "AAPL", 0}, {"APPL", "AAPL", 1},
+		{"MSF", "MSFT", 1}, {"", "ABC", 3}, {"KITTEN", "SITTING", 3},
+	} {
+		if got := editDistance(c.a, c.b); got != c.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestNormalizedSymbolsReachScreener(t *testing.T) {
+	fake := &fakeScreener{}
+	srv := NewServer(fake, &fakePinger{}, testCfg())
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/indicators?symbol=%20aapl%20&type=sma&period=14", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (%s)", rec.Code, rec.Body.String())
+	}
+	if fake.serReq.Symbol != "AAPL" {
+		t.Errorf("series symbol = %q, want AAPL", fake.serReq.Symbol)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/screen?symbols=APPL", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "did you mean AAPL?") {
+		t.Errorf("status = %d, body = %q; want 400 with suggestion", rec.Code, rec.Body.String())
+	}
+}
+
+// Default requests resolve the configured stocks; run under -race this catches
+// any in-place write to the config slice shared by every request.
+func TestConcurrentDefaultSymbolsDoNotShareConfig(t *testing.T) {
+	cfg := testCfg()
+	cfg.Stocks = []string{"AAPL", "MSFT"}
+	srv := NewServer(&fakeScreener{}, &fakePinger{}, cfg).Handler()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/screen", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want 200", rec.Code)
+			}
+		}()
+	}
+	wg.Wait()
+	if cfg.Stocks[0] != "AAPL" || cfg.Stocks[1] != "MSFT" {
+		t.Errorf("config stocks mutated: %v", cfg.Stocks)
+	}
+}
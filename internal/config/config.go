@@ -58,16 +58,30 @@ func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
 
 type Config struct {
 	Server struct {
-		Port int `yaml:"port"`
+		Port              int      `yaml:"port"`
+		CacheTTL          Duration `yaml:"cache_ttl"`
+		CacheMaxEntries   int      `yaml:"cache_max_entries"`
+		ReadHeaderTimeout Duration `yaml:"read_header_timeout"`
+		ReadTimeout       Duration `yaml:"read_timeout"`
+		WriteTimeout      Duration `yaml:"write_timeout"`
+		IdleTimeout       Duration `yaml:"idle_timeout"`
+		MaxHeaderBytes    int      `yaml:"max_header_bytes"`
 	} `yaml:"server"`
 	Collector struct {
-		Enabled           bool `yaml:"enabled"`
-		UseClosedBarsOnly bool `yaml:"use_closed_bars_only"`
+		Enabled           bool     `yaml:"enabled"`
+		UseClosedBarsOnly bool     `yaml:"use_closed_bars_only"`
+		Source            string   `yaml:"source"`         // yahoo (default)
+		RetryBudget       Duration `yaml:"retry_budget"`   // total retry time per fetch; 0 = 30s
+		MaxRetryWait      Duration `yaml:"max_retry_wait"` // cap on one retry wait; 0 = 10s
 		Refresh           struct {
 			Intraday Duration `yaml:"intraday"`
 			Daily    Duration `yaml:"daily"`
 		} `yaml:"refresh"`
 	} `yaml:"collector"`
+	Database struct {
+		Backend      string   `yaml:"backend"` // postgres (default) | memory
+		QueryTimeout Duration `yaml:"query_timeout"`
+	} `yaml:"database"`
 	Stocks     []string `yaml:"stocks"`
 	Timeframes []string `yaml:"timeframes"`
 	Screening  struct {
@@ -123,6 +137,11 @@ func (c *Config) validate() error {
 	if len(c.Timeframes) == 0 {
 		return fmt.Errorf("config: timeframes must not be empty")
 	}
+	switch c.Database.Backend {
+	case "", "postgres", "memory":
+	default:
+		return fmt.Errorf("config: unknown database backend %q (want postgres|memory)", c.Database.Backend)
+	}
 	for _, tf := range c.Timeframes {
 		if _, ok := timeframe.Get(tf); !ok {
 			return fmt.Errorf("config: unknown timeframe %q", tf)
@@ -45,6 +45,13 @@ func TestLoadAndValidate(t *testing.T) {
 	if time.Duration(cfg.Screening.PeakLookback) != 90*24*time.Hour {
 		t.Errorf("peak_lookback = %v, want 3mo", time.Duration(cfg.Screening.PeakLookback))
 	}
+	if time.Duration(cfg.Database.QueryTimeout) != 5*time.Second {
+		t.Errorf("query_timeout = %v, want 5s", time.Duration(cfg.Database.QueryTimeout))
+	}
+	if time.Duration(cfg.Collector.RetryBudget) != time.Minute || time.Duration(cfg.Collector.MaxRetryWait) != 5*time.Second {
+		t.Errorf("retry_budget/max_retry_wait = %v/%v, want 1m/5s",
+			time.Duration(cfg.Collector.RetryBudget), time.Duration(cfg.Collector.MaxRetryWait))
+	}
 }
 
 func TestValidateRejectsBadMatch(t *testing.T) {
@@ -76,6 +83,7 @@ func TestValidateRules(t *testing.T) {
 	mutate := map[string]func(*Config){
 		"empty stocks":       func(c *Config) { c.Stocks = nil },
 		"empty timeframes":   func(c *Config) { c.Timeframes = nil },
+		"unknown backend":    func(c *Config) { c.Database.Backend = "sqlite" },
 		"unknown timeframe":  func(c *Config) { c.Timeframes = []string{"1day"} },
 		"bad match":          func(c *Config) { c.Screening.Match = "nope" },
 		"min:0 match":        func(c *Config) { c.Screening.Match = "min:0" },
@@ -0,0 +1,52 @@
+// Package datasource selects the market-data feed by its config name, so the
+// collector, the health check and the download command share one lookup.
+package datasource
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Ruscigno/stock-screener/internal/collector"
+	"github.com/Ruscigno/stock-screener/internal/datasource/yahoo"
+)
+
+// Yahoo is the default feed, used when collector.source is unset.
+const Yahoo = "yahoo"
+
+// Options are the feed-independent client settings from the collector config;
+// zero fields take each feed's defaults.
+type Options struct {
+	RetryBudget  time.Duration
+	MaxRetryWait time.Duration
+}
+
+var registry = map[string]func(Options) collector.Fetcher{
+	Yahoo: func(o Options) collector.Fetcher {
+		return yahoo.NewWithOptions(nil, yahoo.Options{RetryBudget: o.RetryBudget, MaxRetryWait: o.MaxRetryWait})
+	},
+}
+
+// Names lists the registered feeds, sorted.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for n := range registry {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// New returns a fresh client for the named feed ("" = Yahoo). It is the only
+// check of collector.source: config does not duplicate the list of names.
+func New(name string, o Options) (collector.Fetcher, error) {
+	if name == "" {
+		name = Yahoo
+	}
+	mk, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown data source %q (want %s)", name, strings.Join(Names(), "|"))
+	}
+	return mk(o), nil
+}
@@ -0,0 +1,30 @@
+package datasource
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ruscigno/stock-screener/internal/datasource/yahoo"
+)
+
+func TestNewSelectsYahoo(t *testing.T) {
+	for _, name := range []string{"", "yahoo"} {
+		src, err := New(name, Options{})
+		if err != nil {
+			t.Fatalf("New(%q): %v", name, err)
+		}
+		if _, ok := src.(*yahoo.Client); !ok {
+			t.Errorf("New(%q) = %T, want *yahoo.Client", name, src)
+		}
+	}
+}
+
+func TestNewRejectsUnknownSource(t *testing.T) {
+	src, err := New("mexc", Options{})
+	if err == nil {
+		t.Fatalf("New(mexc) = %T, want error", src)
+	}
+	if !strings.Contains(err.Error(), `unknown data source "mexc" (want yahoo)`) {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -32,15 +33,52 @@ type Client struct {
 	baseURL     string
 	maxAttempts int
 	baseDelay   time.Duration
+	retryBudget time.Duration
+	maxWait     time.Duration
 }
 
-func New() *Client {
+// Retry defaults for Options fields left at zero.
+const (
+	defaultRetryBudget  = 30 * time.Second
+	defaultMaxRetryWait = 10 * time.Second
+)
+
+// Options tune a Client's retries; zero fields take the defaults.
+type Options struct {
+	// RetryBudget caps the total time one Fetch may spend across its retries
+	// (default 30s).
+	RetryBudget time.Duration
+	// MaxRetryWait caps any single wait, including one asked for by a
+	// Retry-After header (default 10s).
+	MaxRetryWait time.Duration
+}
+
+func New() *Client { return NewWithOptions(nil, Options{}) }
+
+// NewWithHTTPClient is New with a caller-supplied HTTP client, e.g. one whose
+// Transport records requests or serves canned responses. nil selects the
+// default client (30s timeout).
+func NewWithHTTPClient(hc *http.Client) *Client { return NewWithOptions(hc, Options{}) }
+
+// NewWithOptions is NewWithHTTPClient with explicit retry limits.
+func NewWithOptions(hc *http.Client, o Options) *Client {
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	if o.RetryBudget <= 0 {
+		o.RetryBudget = defaultRetryBudget
+	}
+	if o.MaxRetryWait <= 0 {
+		o.MaxRetryWait = defaultMaxRetryWait
+	}
 	return &Client{
-		http:        &http.Client{Timeout: 30 * time.Second},
+		http:        hc,
 		userAgent:   "Mozilla/5.0 (stock-screener)",
 		baseURL:     defaultBaseURL,
 		maxAttempts: 3,
 		baseDelay:   500 * time.Millisecond,
+		retryBudget: o.RetryBudget,
+		maxWait:     o.MaxRetryWait,
 	}
 }
 
@@ -63,8 +101,12 @@ func rangeFor(interval string) string {
 // Fetch returns candles for a symbol at a Yahoo interval. If `from` is non-zero
 // it requests period1=from..now (incremental); otherwise it uses the default
 // range for the interval. Transient failures (transport errors, HTTP 429, and
-// 5xx) are retried with exponential backoff up to maxAttempts; the backoff
-// respects ctx cancellation.
+// 5xx) are retried with exponential backoff up to maxAttempts. A Retry-After
+// header lengthens the wait to what the server asked for. Fetch gives up early,
+// rather than sleeping, when the next wait would exceed Options.MaxRetryWait or
+// overrun Options.RetryBudget or ctx's deadline, so one rate-limited symbol
+// stalls the collector for at most MaxRetryWait per retry (the next refresh
+// cycle tries it again). The wait itself respects ctx cancellation.
 func (c *Client) Fetch(ctx context.Context, symbol, interval string, from time.Time) ([]Candle, error) {
 	q := url.Values{}
 	q.Set("interval", interval)
@@ -85,20 +127,34 @@ func (c *Client) Fetch(ctx context.Context, symbol, interval string, from time.T
 	u := c.baseURL + url.PathEscape(symbol) + "?" + q.Encode()
 
 	var lastErr error
+	var retryAfter time.Duration
+	budgetEnd := time.Now().Add(c.retryBudget)
 	for attempt := 0; attempt < c.maxAttempts; attempt++ {
 		if attempt > 0 {
 			delay := c.baseDelay << (attempt - 1) // exponential backoff
+			if retryAfter > delay {
+				delay = retryAfter // the server's hint wins when longer
+			}
+			if delay > c.maxWait {
+				return nil, fmt.Errorf("yahoo %s: retry in %v exceeds max wait %v: %w", symbol, delay, c.maxWait, lastErr)
+			}
+			if time.Now().Add(delay).After(budgetEnd) {
+				return nil, fmt.Errorf("yahoo %s: retry budget %v exhausted after %d attempts: %w", symbol, c.retryBudget, attempt, lastErr)
+			}
+			if dl, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(dl) {
+				return nil, fmt.Errorf("yahoo %s: next retry in %v exceeds context deadline: %w", symbol, delay, lastErr)
+			}
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
 			case <-time.After(delay):
 			}
 		}
-		candles, retryable, err := c.attempt(ctx, u, symbol)
+		candles, wait, retryable, err := c.attempt(ctx, u, symbol)
 		if err == nil {
 			return candles, nil
 		}
-		lastErr = err
+		lastErr, retryAfter = err, wait
 		if !retryable {
 			return nil, err
 		}
@@ -107,35 +163,55 @@ func (c *Client) Fetch(ctx context.Context, symbol, interval string, from time.T
 }
 
 // attempt performs one HTTP request. retryable is true for failures worth
-// retrying (transport error, HTTP 429, HTTP 5xx).
-func (c *Client) attempt(ctx context.Context, u, symbol string) (candles []Candle, retryable bool, err error) {
+// retrying (transport error, HTTP 429, HTTP 5xx); retryAfter is the server's
+// Retry-After hint on such a response, or 0.
+func (c *Client) attempt(ctx context.Context, u, symbol string) (candles []Candle, retryAfter time.Duration, retryable bool, err error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
-		return nil, false, err
+		return nil, 0, false, err
 	}
 	req.Header.Set("User-Agent", c.userAgent)
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, true, err // transport/network error
+		return nil, 0, true, err // transport/network error
 	}
 	defer resp.Body.Close()
 	// Cap the body: this is an unofficial upstream, so a hostile/compromised
 	// response must not be able to exhaust memory.
 	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
 	if err != nil {
-		return nil, true, err
+		return nil, 0, true, err
 	}
 	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-		return nil, true, fmt.Errorf("yahoo %s: status %d", symbol, resp.StatusCode)
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return nil, wait, true, fmt.Errorf("yahoo %s: status %d", symbol, resp.StatusCode)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, false, fmt.Errorf("yahoo %s: status %d", symbol, resp.StatusCode)
+		return nil, 0, false, fmt.Errorf("yahoo %s: status %d", symbol, resp.StatusCode)
 	}
 	candles, err = parseChart(body)
 	if err != nil {
-		return nil, false, err
+		return nil, 0, false, err
+	}
+	return candles, 0, false, nil
+}
+
+// parseRetryAfter decodes a Retry-After header given as delay-seconds or an
+// HTTP date. Missing, malformed, or past values yield 0.
+func parseRetryAfter(h string, now time.Time) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil && t.After(now) {
+		return t.Sub(now)
 	}
-	return candles, false, nil
+	return 0
 }
 
 type chartResponse struct {
@@ -1,11 +1,14 @@
 package yahoo
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -43,7 +46,11 @@ func TestParseChartError(t *testing.T) {
 }
 
 func testClient(server *httptest.Server) *Client {
-	c := New()
+	return testClientWithOptions(server, Options{})
+}
+
+func testClientWithOptions(server *httptest.Server, o Options) *Client {
+	c := NewWithOptions(nil, o)
 	c.baseURL = server.URL + "/"
 	c.baseDelay = time.Millisecond // keep tests fast
 	return c
@@ -147,3 +154,171 @@ func TestFetchQueryParamsByInterval(t *testing.T) {
 		t.Errorf("incremental: want period1=%d & no range, got period1=%q range=%q", from.Unix(), gotQuery.Get("period1"), gotQuery.Get("range"))
 	}
 }
+
+// recordingTransport serves a canned body and records every request, so the
+// client can be exercised without a server or network.
+type recordingTransport struct {
+	body []byte
+	reqs []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.reqs = append(rt.reqs, r)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(rt.body)),
+		Header:     http.Header{},
+		Request:    r,
+	}, nil
+}
+
+func TestNewWithHTTPClientUsesInjectedTransport(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/aapl_1d.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	rt := &recordingTransport{body: fixture}
+	c := NewWithHTTPClient(&http.Client{Transport: rt})
+
+	candles, err := c.Fetch(context.Background(), "AAPL", "1d", time.Time{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(candles) != 2 {
+		t.Errorf("candles = %d, want 2", len(candles))
+	}
+	if len(rt.reqs) != 1 {
+		t.Fatalf("transport saw %d requests, want 1", len(rt.reqs))
+	}
+	r := rt.reqs[0]
+	if r.URL.Host != "query1.finance.yahoo.com" || r.URL.Path != "/v8/finance/chart/AAPL" {
+		t.Errorf("request URL = %s", r.URL)
+	}
+	if r.Header.Get("User-Agent") == "" {
+		t.Error("User-Agent header not set")
+	}
+}
+
+func TestNewWithNilHTTPClientUsesDefault(t *testing.T) {
+	c := NewWithHTTPClient(nil)
+	if c.http == nil || c.http.Timeout != 30*time.Second {
+		t.Errorf("default http client = %+v, want 30s timeout", c.http)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":                              0,
+		"3":                             3 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"Mon, 01 Jun 2026 12:00:10 GMT": 10 * time.Second,
+		"Mon, 01 Jun 2026 11:59:00 GMT": 0, // in the past
+	}
+	for in, want := range cases {
+		if got := parseRetryAfter(in, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestFetchHonorsRetryAfter(t *testing.T) {
+	fixture, _ := os.ReadFile("testdata/aapl_1d.json")
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	if _, err := testClient(srv).Fetch(context.Background(), "AAPL", "1d", time.Time{}); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	// baseDelay is 1ms in tests, so only the header explains a ~1s wait.
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want >= 1s (Retry-After)", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestFetchGivesUpWhenRetryAfterExceedsBudget(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c := testClientWithOptions(srv, Options{RetryBudget: time.Second})
+
+	start := time.Now()
+	_, err := c.Fetch(context.Background(), "AAPL", "1d", time.Time{})
+	if err == nil || !strings.Contains(err.Error(), "retry budget") {
+		t.Fatalf("err = %v, want retry budget exhausted", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %v, want an immediate give-up instead of sleeping", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestFetchGivesUpBeforeContextDeadline(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, err := testClient(srv).Fetch(ctx, "AAPL", "1d", time.Time{}); err == nil {
+		t.Fatal("expected error when the next retry would overrun the deadline")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %v, want an immediate give-up", elapsed)
+	}
+}
+
+func TestFetchGivesUpWhenRetryAfterExceedsMaxWait(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "29")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	// The budget alone would allow the 29s wait; the per-wait cap must not.
+	c := testClientWithOptions(srv, Options{RetryBudget: time.Minute, MaxRetryWait: 2 * time.Second})
+
+	start := time.Now()
+	_, err := c.Fetch(context.Background(), "AAPL", "1d", time.Time{})
+	if err == nil || !strings.Contains(err.Error(), "exceeds max wait 2s") {
+		t.Fatalf("err = %v, want max wait exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %v, want an immediate give-up instead of sleeping", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestNewWithOptionsDefaults(t *testing.T) {
+	c := NewWithOptions(nil, Options{})
+	if c.retryBudget != defaultRetryBudget || c.maxWait != defaultMaxRetryWait {
+		t.Errorf("budget = %v, max wait = %v, want defaults %v/%v", c.retryBudget, c.maxWait, defaultRetryBudget, defaultMaxRetryWait)
+	}
+}
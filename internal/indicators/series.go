@@ -0,0 +1,39 @@
+package indicators
+
+import "strings"
+
+// Kinds accepted by Compute and MinBars (matched case-insensitively).
+const (
+	KindSMA = "sma"
+	KindEMA = "ema"
+	KindRSI = "rsi"
+)
+
+// Kinds lists the indicator kinds Compute understands, in canonical order.
+var Kinds = []string{KindSMA, KindEMA, KindRSI}
+
+// Compute returns the named indicator over closes with the given period. ok is
+// false for an unknown kind.
+func Compute(kind string, closes []float64, period int) (out []float64, ok bool) {
+	switch strings.ToLower(kind) {
+	case KindSMA:
+		return SMA(closes, period), true
+	case KindEMA:
+		return EMA(closes, period), true
+	case KindRSI:
+		return RSI(closes, period), true
+	}
+	return nil, false
+}
+
+// MinBars is the number of closes kind needs before it produces its first
+// value (SMA/EMA: period; RSI: period+1), or 0 for an unknown kind.
+func MinBars(kind string, period int) int {
+	switch strings.ToLower(kind) {
+	case KindSMA, KindEMA:
+		return period
+	case KindRSI:
+		return period + 1
+	}
+	return 0
+}
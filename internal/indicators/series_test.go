@@ -0,0 +1,63 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeKnownValues(t *testing.T) {
+	closes := []float64{1, 2, 3, 4}
+	cases := []struct {
+		kind string
+		want []float64
+	}{
+		{"SMA", []float64{math.NaN(), 1.5, 2.5, 3.5}},
+		// EMA seed = (1+2)/2 = 1.5, alpha = 2/3: 2.5, then 2/3*4 + 1/3*2.5 = 3.5.
+		{"ema", []float64{math.NaN(), 1.5, 2.5, 3.5}},
+		// Strictly rising closes -> no losses -> RSI 100 from index = period.
+		{"Rsi", []float64{math.NaN(), math.NaN(), 100, 100}},
+	}
+	for _, c := range cases {
+		got, ok := Compute(c.kind, closes, 2)
+		if !ok {
+			t.Fatalf("Compute(%q) not ok", c.kind)
+		}
+		if len(got) != len(c.want) {
+			t.Fatalf("%s: len = %d, want %d", c.kind, len(got), len(c.want))
+		}
+		for i := range c.want {
+			if math.IsNaN(c.want[i]) != math.IsNaN(got[i]) || (!math.IsNaN(c.want[i]) && !approx(got[i], c.want[i])) {
+				t.Errorf("%s: got[%d] = %v, want %v", c.kind, i, got[i], c.want[i])
+			}
+		}
+	}
+}
+
+func TestComputeUnknownKind(t *testing.T) {
+	if _, ok := Compute("macd", []float64{1, 2, 3}, 2); ok {
+		t.Error("Compute(macd) ok = true, want false")
+	}
+	if n := MinBars("macd", 2); n != 0 {
+		t.Errorf("MinBars(macd) = %d, want 0", n)
+	}
+}
+
+func TestMinBarsMatchesFirstValue(t *testing.T) {
+	closes := make([]float64, 30)
+	for i := range closes {
+		closes[i] = float64(i%5) + 1
+	}
+	for _, kind := range Kinds {
+		got, _ := Compute(kind, closes, 14)
+		first := -1
+		for i, v := range got {
+			if !math.IsNaN(v) {
+				first = i
+				break
+			}
+		}
+		if want := MinBars(kind, 14) - 1; first != want {
+			t.Errorf("%s: first value at %d, want %d (MinBars-1)", kind, first, want)
+		}
+	}
+}
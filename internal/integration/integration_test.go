@@ -53,7 +53,7 @@ func TestCollectThenScreen(t *testing.T) {
 	}
 	ctx := context.Background()
 
-	store, err := storage.NewPostgresStore(dsn)
+	store, err := storage.NewPostgresStore(context.Background(), dsn, 0)
 	if err != nil {
 		t.Fatalf("connect: %v", err)
 	}
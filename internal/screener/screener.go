@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/Ruscigno/stock-screener/internal/config"
@@ -25,7 +26,7 @@ func New(store storage.Store, cfg *config.Config) *Screener {
 }
 
 func (s *Screener) Screen(ctx context.Context, req Request) (Result, error) {
-	var res Result
+	res := Result{AsOf: time.Now().UTC()}
 	for _, symbol := range req.Symbols {
 		for _, tfName := range req.Timeframes {
 			tf, ok := timeframe.Get(tfName)
@@ -33,7 +34,7 @@ func (s *Screener) Screen(ctx context.Context, req Request) (Result, error) {
 				res.Warnings = append(res.Warnings, Warning{symbol, tfName, "unknown timeframe"})
 				continue
 			}
-			bars, err := s.loadBars(ctx, symbol, tf)
+			bars, err := s.loadBars(ctx, symbol, tf, requiredBars(s.cfg, tf))
 			if err != nil {
 				res.Warnings = append(res.Warnings, Warning{symbol, tfName, "load error: " + err.Error()})
 				continue
@@ -52,8 +53,9 @@ func (s *Screener) Screen(ctx context.Context, req Request) (Result, error) {
 	return res, nil
 }
 
-func (s *Screener) loadBars(ctx context.Context, symbol string, tf timeframe.TF) ([]storage.Bar, error) {
-	need := requiredBars(s.cfg, tf)
+// loadBars returns the most recent `need` bars of tf for symbol, resampling
+// derived timeframes from their parent.
+func (s *Screener) loadBars(ctx context.Context, symbol string, tf timeframe.TF, need int) ([]storage.Bar, error) {
 	if tf.Native {
 		return s.store.GetBars(ctx, symbol, tf.Name, need)
 	}
@@ -98,6 +100,50 @@ func requiredBars(cfg *config.Config, tf timeframe.TF) int {
 	return warmup
 }
 
+// seriesWarmup is the extra history Series loads ahead of the returned window
+// so recursive indicators (EMA, RSI) have settled by the first returned value.
+const seriesWarmup = 50
+
+// Series computes one indicator over the stored closes of (symbol, timeframe)
+// and returns its last req.Points values, oldest first. It returns an
+// *InsufficientDataError when fewer bars are stored than the indicator needs.
+func (s *Screener) Series(ctx context.Context, req SeriesRequest) ([]SeriesPoint, error) {
+	tf, ok := timeframe.Get(req.Timeframe)
+	if !ok {
+		return nil, fmt.Errorf("unknown timeframe %q", req.Timeframe)
+	}
+	minBars := indicators.MinBars(req.Kind, req.Period)
+	if minBars == 0 {
+		return nil, fmt.Errorf("unknown indicator %q", req.Kind)
+	}
+	bars, err := s.loadBars(ctx, req.Symbol, tf, minBars+req.Points+seriesWarmup)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) < minBars {
+		return nil, &InsufficientDataError{
+			Indicator: fmt.Sprintf("%s(%d)", strings.ToLower(req.Kind), req.Period),
+			Need:      minBars, Have: len(bars),
+		}
+	}
+	closes := make([]float64, len(bars))
+	for i, b := range bars {
+		closes[i] = b.Close
+	}
+	series, _ := indicators.Compute(req.Kind, closes, req.Period)
+	start := len(series) - req.Points
+	if start < 0 {
+		start = 0
+	}
+	out := make([]SeriesPoint, 0, len(series)-start)
+	for i := start; i < len(series); i++ {
+		if !math.IsNaN(series[i]) {
+			out = append(out, SeriesPoint{Time: bars[i].Time, Value: series[i]})
+		}
+	}
+	return out, nil
+}
+
 func (s *Screener) evaluate(symbol, tfName string, bars []storage.Bar, req Request) (*Row, []Warning) {
 	var warns []Warning
 	closes := make([]float64, len(bars))
@@ -2,6 +2,7 @@ package screener
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -226,3 +227,66 @@ func TestLoadBarsBoundsAndScalesDerived(t *testing.T) {
 		t.Errorf("derived load: tf=%q limit=%d, want 1h/%d", derStore.lastTF, derStore.lastLimit, wantLimit)
 	}
 }
+
+func TestSeriesReturnsTrailingPoints(t *testing.T) {
+	bars := buildBars([]float64{1, 2, 3, 4, 5, 6})
+	s := New(&fakeStore{bars: bars}, testConfig())
+	got, err := s.Series(context.Background(), SeriesRequest{
+		Symbol: "AAA", Timeframe: "1d", Kind: "SMA", Period: 2, Points: 3,
+	})
+	if err != nil {
+		t.Fatalf("Series: %v", err)
+	}
+	want := []float64{3.5, 4.5, 5.5}
+	if len(got) != len(want) {
+		t.Fatalf("points = %d, want %d", len(got), len(want))
+	}
+	for i, p := range got {
+		if p.Value != want[i] {
+			t.Errorf("point %d = %v, want %v", i, p.Value, want[i])
+		}
+		if !p.Time.Equal(bars[3+i].Time) {
+			t.Errorf("point %d time = %v, want %v", i, p.Time, bars[3+i].Time)
+		}
+	}
+}
+
+func TestSeriesSkipsWarmupNaNs(t *testing.T) {
+	// Asking for more points than have a value returns only the defined ones.
+	s := New(&fakeStore{bars: buildBars([]float64{1, 2, 3})}, testConfig())
+	got, err := s.Series(context.Background(), SeriesRequest{
+		Symbol: "AAA", Timeframe: "1d", Kind: "ema", Period: 2, Points: 10,
+	})
+	if err != nil {
+		t.Fatalf("Series: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("points = %d, want 2 (warmup NaN dropped)", len(got))
+	}
+}
+
+func TestSeriesInsufficientData(t *testing.T) {
+	s := New(&fakeStore{bars: buildBars([]float64{1, 2, 3})}, testConfig())
+	_, err := s.Series(context.Background(), SeriesRequest{
+		Symbol: "AAA", Timeframe: "1d", Kind: "rsi", Period: 14, Points: 1,
+	})
+	var ide *InsufficientDataError
+	if !errors.As(err, &ide) {
+		t.Fatalf("err = %v, want *InsufficientDataError", err)
+	}
+	if ide.Need != 15 || ide.Have != 3 {
+		t.Errorf("need/have = %d/%d, want 15/3", ide.Need, ide.Have)
+	}
+}
+
+func TestScreenStampsAsOf(t *testing.T) {
+	s := New(&fakeStore{bars: buildBars([]float64{10, 11, 12})}, testConfig())
+	before := time.Now().UTC()
+	res, err := s.Screen(context.Background(), Request{Symbols: []string{"AAA"}, Timeframes: []string{"1d"}, Match: "any"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AsOf.Before(before) || res.AsOf.After(time.Now().UTC()) || res.AsOf.Location() != time.UTC {
+		t.Errorf("AsOf = %v, want the run's start in UTC", res.AsOf)
+	}
+}
@@ -1,6 +1,9 @@
 package screener
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Request parameters for one screen call (already defaulted from config).
 type Request struct {
@@ -43,10 +46,39 @@ type Warning struct {
 }
 
 type Result struct {
+	AsOf     time.Time // when the run started (UTC); bars are read after it
 	Rows     []Row
 	Warnings []Warning
 }
 
+// SeriesRequest asks for one indicator's recent values for a (symbol,
+// timeframe). Kind is one of indicators.Kinds.
+type SeriesRequest struct {
+	Symbol    string
+	Timeframe string
+	Kind      string
+	Period    int
+	Points    int // trailing values to return
+}
+
+// SeriesPoint is one indicator value at a bar-open time.
+type SeriesPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// InsufficientDataError reports that fewer bars are stored than an indicator
+// needs to produce its first value.
+type InsufficientDataError struct {
+	Indicator string
+	Need      int
+	Have      int
+}
+
+func (e *InsufficientDataError) Error() string {
+	return fmt.Sprintf("insufficient_data: %s needs %d bars, have %d", e.Indicator, e.Need, e.Have)
+}
+
 // Indicator name constants.
 const (
 	IndRSI      = "rsi"
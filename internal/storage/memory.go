@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store for ephemeral deployments that run
+// without Postgres. Bars live only as long as the process; the collector's
+// initial full pass refills them on start.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	series map[seriesKey][]Bar // ascending by Time, unique per Time
+}
+
+type seriesKey struct{ symbol, timeframe string }
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{series: map[seriesKey][]Bar{}}
+}
+
+func (m *MemoryStore) Migrate(context.Context) error { return nil }
+
+// UpsertBars inserts bars, replacing any stored bar with the same (symbol,
+// timeframe, time) — the same semantics as the Postgres ON CONFLICT upsert.
+func (m *MemoryStore) UpsertBars(_ context.Context, bars []Bar) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, b := range bars {
+		b.Time = b.Time.UTC()
+		k := seriesKey{b.Symbol, b.Timeframe}
+		s := m.series[k]
+		i := sort.Search(len(s), func(i int) bool { return !s[i].Time.Before(b.Time) })
+		switch {
+		case i < len(s) && s[i].Time.Equal(b.Time):
+			s[i] = b
+		case i == len(s): // common case: bars arrive in time order
+			s = append(s, b)
+		default:
+			s = append(s, Bar{})
+			copy(s[i+1:], s[i:])
+			s[i] = b
+		}
+		m.series[k] = s
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetBars(_ context.Context, symbol, timeframe string, limit int) ([]Bar, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s := m.series[seriesKey{symbol, timeframe}]
+	if limit > 0 && limit < len(s) {
+		s = s[len(s)-limit:]
+	}
+	if len(s) == 0 {
+		return nil, nil
+	}
+	out := make([]Bar, len(s))
+	copy(out, s)
+	return out, nil
+}
+
+func (m *MemoryStore) LastBarTime(_ context.Context, symbol, timeframe string) (time.Time, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s := m.series[seriesKey{symbol, timeframe}]
+	if len(s) == 0 {
+		return time.Time{}, false, nil
+	}
+	return s[len(s)-1].Time, true, nil
+}
+
+func (m *MemoryStore) Ping(context.Context) error { return nil }
+func (m *MemoryStore) Close() error               { return nil }
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreSuite(t *testing.T) {
+	runStoreSuite(t, NewMemoryStore())
+}
+
+func TestMemoryStoreGetBarsReturnsCopy(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	_ = s.UpsertBars(ctx, []Bar{{Symbol: "AAA", Timeframe: "1d", Time: t0, Close: 1}})
+	got, _ := s.GetBars(ctx, "AAA", "1d", 0)
+	got[0].Close = 99
+	again, _ := s.GetBars(ctx, "AAA", "1d", 0)
+	if again[0].Close != 1 {
+		t.Errorf("stored close = %v, want 1 (caller mutation leaked into the store)", again[0].Close)
+	}
+}
+
+func TestMemoryStoreConcurrentAccess(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = s.UpsertBars(ctx, []Bar{{Symbol: "AAA", Timeframe: "1d", Time: t0.AddDate(0, 0, i)}})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, _ = s.GetBars(ctx, "AAA", "1d", 3)
+		}()
+	}
+	wg.Wait()
+	if got, _ := s.GetBars(ctx, "AAA", "1d", 0); len(got) != 8 {
+		t.Errorf("bars = %d, want 8", len(got))
+	}
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -32,11 +33,19 @@ type Store interface {
 	Close() error
 }
 
+// defaultQueryTimeout bounds each query when no timeout is configured.
+const defaultQueryTimeout = 30 * time.Second
+
 type PostgresStore struct {
-	db *sql.DB
+	db           *sql.DB
+	queryTimeout time.Duration // per statement; NewPostgresStore never leaves it 0
 }
 
-func NewPostgresStore(dsn string) (*PostgresStore, error) {
+// NewPostgresStore connects to dsn; ctx bounds the initial connect and ping.
+// Every statement the store issues is bounded by queryTimeout
+// (defaultQueryTimeout when <= 0) so a hung database cannot block a request or
+// collector cycle indefinitely.
+func NewPostgresStore(ctx context.Context, dsn string, queryTimeout time.Duration) (*PostgresStore, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, err
@@ -46,10 +55,33 @@ func NewPostgresStore(dsn string) (*PostgresStore, error) {
 	db.SetMaxOpenConns(10)
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(30 * time.Minute)
-	if err := db.Ping(); err != nil {
+	// lib/pq honors ctx only while dialing, not during the startup handshake,
+	// so also stop waiting when ctx is done: a server that accepts but never
+	// answers must not hang the caller.
+	pinged := make(chan error, 1)
+	go func() { pinged <- db.PingContext(ctx) }()
+	select {
+	case err = <-pinged:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		db.Close()
 		return nil, err
 	}
-	return &PostgresStore{db: db}, nil
+	if queryTimeout <= 0 {
+		queryTimeout = defaultQueryTimeout
+	}
+	return &PostgresStore{db: db, queryTimeout: queryTimeout}, nil
+}
+
+// withTimeout derives a context bounded by the store's query timeout. A
+// shorter caller deadline still wins.
+func (s *PostgresStore) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.queryTimeout)
 }
 
 const schema = `
@@ -68,6 +100,8 @@ CREATE INDEX IF NOT EXISTS bars_symbol_tf_ts ON bars (symbol, timeframe, ts DESC
 `
 
 func (s *PostgresStore) Migrate(ctx context.Context) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 	_, err := s.db.ExecContext(ctx, schema)
 	return err
 }
@@ -76,12 +110,22 @@ func (s *PostgresStore) UpsertBars(ctx context.Context, bars []Bar) error {
 	if len(bars) == 0 {
 		return nil
 	}
-	tx, err := s.db.BeginTx(ctx, nil)
+	// Each statement gets the query timeout, and the transaction as a whole gets
+	// one per statement plus BEGIN and COMMIT, so a large backfill isn't cut off
+	// but a DB hung at BEGIN or COMMIT can't block the collector indefinitely.
+	txCtx, cancelTx := ctx, context.CancelFunc(func() {})
+	if s.queryTimeout > 0 {
+		txCtx, cancelTx = context.WithTimeout(ctx, s.queryTimeout*time.Duration(len(bars)+2))
+	}
+	defer cancelTx()
+	tx, err := s.db.BeginTx(txCtx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
-	stmt, err := tx.PrepareContext(ctx, `
+	pctx, cancel := s.withTimeout(txCtx)
+	defer cancel()
+	stmt, err := tx.PrepareContext(pctx, `
 		INSERT INTO bars (symbol, timeframe, ts, open, high, low, close, volume)
 		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
 		ON CONFLICT (symbol, timeframe, ts) DO UPDATE SET
@@ -92,12 +136,28 @@ func (s *PostgresStore) UpsertBars(ctx context.Context, bars []Bar) error {
 	}
 	defer stmt.Close()
 	for _, b := range bars {
-		if _, err := stmt.ExecContext(ctx, b.Symbol, b.Timeframe, b.Time.UTC(),
-			b.Open, b.High, b.Low, b.Close, b.Volume); err != nil {
+		qctx, cancel := s.withTimeout(txCtx)
+		_, err := stmt.ExecContext(qctx, b.Symbol, b.Timeframe, b.Time.UTC(),
+			b.Open, b.High, b.Low, b.Close, b.Volume)
+		cancel()
+		if err != nil {
 			return err
 		}
 	}
-	return tx.Commit()
+	// Close the statement now: once the commit below holds the connection, a
+	// deferred Close would block on it until the commit finished.
+	stmt.Close()
+	// database/sql passes no context to the driver's Commit, so wait on txCtx
+	// here. On timeout the outcome is unknown, which is safe: the upsert is
+	// idempotent and the next cycle rewrites the same bars.
+	committed := make(chan error, 1)
+	go func() { committed <- tx.Commit() }()
+	select {
+	case err := <-committed:
+		return err
+	case <-txCtx.Done():
+		return fmt.Errorf("commit: %w", txCtx.Err())
+	}
 }
 
 func (s *PostgresStore) GetBars(ctx context.Context, symbol, timeframe string, limit int) ([]Bar, error) {
@@ -108,6 +168,8 @@ func (s *PostgresStore) GetBars(ctx context.Context, symbol, timeframe string, l
 		q += " LIMIT $3"
 		args = append(args, limit)
 	}
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 	rows, err := s.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
@@ -132,6 +194,8 @@ func (s *PostgresStore) GetBars(ctx context.Context, symbol, timeframe string, l
 }
 
 func (s *PostgresStore) LastBarTime(ctx context.Context, symbol, timeframe string) (time.Time, bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 	var ts time.Time
 	err := s.db.QueryRowContext(ctx,
 		`SELECT ts FROM bars WHERE symbol=$1 AND timeframe=$2 ORDER BY ts DESC LIMIT 1`,
@@ -145,5 +209,10 @@ func (s *PostgresStore) LastBarTime(ctx context.Context, symbol, timeframe strin
 	return ts.UTC(), true, nil
 }
 
-func (s *PostgresStore) Ping(ctx context.Context) error { return s.db.PingContext(ctx) }
-func (s *PostgresStore) Close() error                   { return s.db.Close() }
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.db.PingContext(ctx)
+}
+
+func (s *PostgresStore) Close() error { return s.db.Close() }
@@ -2,6 +2,9 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"testing"
 	"time"
 
@@ -109,3 +112,113 @@ func TestLastBarTime(t *testing.T) {
 		t.Errorf("unmet expectations: %v", err)
 	}
 }
+
+func TestGetBarsHonorsQueryTimeout(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	s := &PostgresStore{db: db, queryTimeout: 20 * time.Millisecond}
+
+	// A query that would take a second must be abandoned at the timeout.
+	mock.ExpectQuery("SELECT .+ FROM bars").
+		WithArgs("AAA", "1d").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol"}))
+	start := time.Now()
+	if _, err := s.GetBars(context.Background(), "AAA", "1d", 0); err == nil {
+		t.Fatal("GetBars succeeded, want timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetBars took %v, want it cut off near the 20ms timeout", elapsed)
+	}
+}
+
+func TestUpsertBarsTimeoutIsPerStatement(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	s := &PostgresStore{db: db, queryTimeout: 50 * time.Millisecond}
+
+	// Three statements of 30ms each exceed the timeout in total but not
+	// individually, so the batch must commit.
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO bars")
+	for i := 0; i < 3; i++ {
+		mock.ExpectExec("INSERT INTO bars").
+			WillDelayFor(30 * time.Millisecond).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]Bar, 3)
+	for i := range bars {
+		bars[i] = Bar{Symbol: "AAA", Timeframe: "1d", Time: t0.AddDate(0, 0, i)}
+	}
+	if err := s.UpsertBars(context.Background(), bars); err != nil {
+		t.Fatalf("UpsertBars: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestUpsertBarsReturnsWhenBeginHangs(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	s := &PostgresStore{db: db, queryTimeout: 20 * time.Millisecond}
+
+	mock.ExpectBegin().WillDelayFor(time.Second)
+	start := time.Now()
+	err := s.UpsertBars(context.Background(), []Bar{{Symbol: "AAA", Timeframe: "1d", Time: time.Now()}})
+	if err == nil {
+		t.Fatal("UpsertBars succeeded, want timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("UpsertBars took %v, want it cut off near the transaction timeout", elapsed)
+	}
+}
+
+func TestUpsertBarsReturnsWhenCommitHangs(t *testing.T) {
+	release := make(chan struct{})
+	db := sql.OpenDB(hangingCommitDriver{release})
+	defer db.Close()
+	defer close(release)
+	s := &PostgresStore{db: db, queryTimeout: 20 * time.Millisecond}
+
+	start := time.Now()
+	err := s.UpsertBars(context.Background(), []Bar{{Symbol: "AAA", Timeframe: "1d", Time: time.Now()}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want commit deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("UpsertBars took %v, want it cut off near the transaction timeout", elapsed)
+	}
+}
+
+// hangingCommitDriver is a minimal driver whose Commit blocks until release
+// closes, standing in for a COMMIT stuck on a synchronous standby (sqlmock
+// cannot delay Commit). Statements succeed immediately.
+type hangingCommitDriver struct{ release chan struct{} }
+
+func (d hangingCommitDriver) Open(string) (driver.Conn, error) { return hangingConn(d), nil }
+func (d hangingCommitDriver) Connect(context.Context) (driver.Conn, error) {
+	return hangingConn(d), nil
+}
+func (d hangingCommitDriver) Driver() driver.Driver { return d }
+
+type hangingConn struct{ release chan struct{} }
+
+func (c hangingConn) Prepare(string) (driver.Stmt, error) { return okStmt{}, nil }
+func (c hangingConn) Close() error                        { return nil }
+func (c hangingConn) Begin() (driver.Tx, error)           { return hangingTx(c), nil }
+
+type hangingTx struct{ release chan struct{} }
+
+func (t hangingTx) Commit() error   { <-t.release; return nil }
+func (t hangingTx) Rollback() error { return nil }
+
+type okStmt struct{}
+
+func (okStmt) Close() error                               { return nil }
+func (okStmt) NumInput() int                              { return -1 }
+func (okStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (okStmt) Query([]driver.Value) (driver.Rows, error)  { return nil, errors.New("not supported") }
@@ -12,7 +12,7 @@ func testStore(t *testing.T) *PostgresStore {
 	if dsn == "" {
 		t.Skip("set SCREENER_TEST_DSN to run storage integration tests")
 	}
-	s, err := NewPostgresStore(dsn)
+	s, err := NewPostgresStore(context.Background(), dsn, 0)
 	if err != nil {
 		t.Fatalf("connect: %v", err)
 	}
@@ -27,6 +27,14 @@ func testStore(t *testing.T) *PostgresStore {
 func TestUpsertAndGet(t *testing.T) {
 	s := testStore(t)
 	defer s.Close()
+	runStoreSuite(t, s)
+}
+
+// runStoreSuite is the behavior every Store backend must share; it runs
+// against Postgres here and against MemoryStore in memory_test.go. It uses the
+// "TST" symbol, which testStore clears beforehand.
+func runStoreSuite(t *testing.T, s Store) {
+	t.Helper()
 	ctx := context.Background()
 	t0 := time.Date(2026, 6, 16, 0, 0, 0, 0, time.UTC)
 	bars := []Bar{
@@ -60,4 +68,70 @@ func TestUpsertAndGet(t *testing.T) {
 	if !last.Equal(t0.AddDate(0, 0, 1)) {
 		t.Errorf("last = %v, want %v", last, t0.AddDate(0, 0, 1))
 	}
+
+	// An out-of-order insert lands in place; limit returns the most recent
+	// bars, still ascending.
+	earlier := Bar{Symbol: "TST", Timeframe: "1d", Time: t0.AddDate(0, 0, -1), Close: 0.9}
+	if err := s.UpsertBars(ctx, []Bar{earlier}); err != nil {
+		t.Fatalf("upsert earlier: %v", err)
+	}
+	all, err := s.GetBars(ctx, "TST", "1d", 0)
+	if err != nil || len(all) != 3 || !all[0].Time.Equal(earlier.Time) {
+		t.Fatalf("after out-of-order insert: %d bars, err=%v, want 3 starting at %v", len(all), err, earlier.Time)
+	}
+	recent, err := s.GetBars(ctx, "TST", "1d", 2)
+	if err != nil {
+		t.Fatalf("get limit: %v", err)
+	}
+	if len(recent) != 2 || !recent[0].Time.Equal(t0) || !recent[1].Time.Equal(t0.AddDate(0, 0, 1)) {
+		t.Errorf("limit 2 = %+v, want the two most recent ascending", recent)
+	}
+
+	// Other series are independent; an empty series reports no last bar.
+	if got, err := s.GetBars(ctx, "TST", "1wk", 0); err != nil || len(got) != 0 {
+		t.Errorf("other timeframe: %d bars, err=%v, want 0", len(got), err)
+	}
+	if _, ok, err := s.LastBarTime(ctx, "TST", "1wk"); err != nil || ok {
+		t.Errorf("LastBarTime(empty): ok=%v err=%v, want false/nil", ok, err)
+	}
+}
+
+// TestQueryTimeoutBoundsStoreMethods blocks the bars table with a lock held by
+// another connection, so each store method hangs until its own query timeout
+// fires; a method that skipped withTimeout would wait out the 5s caller ctx.
+func TestQueryTimeoutBoundsStoreMethods(t *testing.T) {
+	s := testStore(t)
+	defer s.Close()
+	s.queryTimeout = 50 * time.Millisecond
+
+	ctx := context.Background()
+	lock, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer lock.Rollback()
+	if _, err := lock.ExecContext(ctx, "LOCK TABLE bars IN ACCESS EXCLUSIVE MODE"); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	bar := Bar{Symbol: "TST", Timeframe: "1d", Time: time.Date(2026, 6, 16, 0, 0, 0, 0, time.UTC), Close: 1}
+	for name, call := range map[string]func(context.Context) error{
+		"GetBars": func(ctx context.Context) error { _, err := s.GetBars(ctx, "TST", "1d", 0); return err },
+		"LastBarTime": func(ctx context.Context) error {
+			_, _, err := s.LastBarTime(ctx, "TST", "1d")
+			return err
+		},
+		"UpsertBars": func(ctx context.Context) error { return s.UpsertBars(ctx, []Bar{bar}) },
+	} {
+		callerCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		start := time.Now()
+		err := call(callerCtx)
+		cancel()
+		if err == nil {
+			t.Errorf("%s succeeded against a locked table, want a timeout error", name)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("%s took %v, want it cut off near the 50ms query timeout", name, elapsed)
+		}
+	}
 }
@@ -2,23 +2,28 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"runtime/debug"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/Ruscigno/stock-screener/internal/api"
 	"github.com/Ruscigno/stock-screener/internal/collector"
 	"github.com/Ruscigno/stock-screener/internal/config"
-	"github.com/Ruscigno/stock-screener/internal/datasource/yahoo"
+	"github.com/Ruscigno/stock-screener/internal/datasource"
 	"github.com/Ruscigno/stock-screener/internal/screener"
 	"github.com/Ruscigno/stock-screener/internal/storage"
+	"github.com/Ruscigno/stock-screener/internal/timeframe"
 )
 
 // dsnFromEnv builds the Postgres DSN from environment variables. TLS mode is
@@ -49,6 +54,74 @@ func dsnFromEnv() (string, error) {
 	return dsn.String(), nil
 }
 
+// openStore opens the configured storage backend: Postgres (DSN from the
+// environment) by default, or an in-memory store for ephemeral runs.
+func openStore(ctx context.Context, cfg *config.Config) (storage.Store, error) {
+	if cfg.Database.Backend == "memory" {
+		return storage.NewMemoryStore(), nil
+	}
+	dsn, err := dsnFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("db env: %w", err)
+	}
+	store, err := storage.NewPostgresStore(ctx, dsn, time.Duration(cfg.Database.QueryTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("db connect: %w", err)
+	}
+	return store, nil
+}
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X main.version=v1.2.0 -X main.gitCommit=$(git rev-parse HEAD) -X main.gitDirty=true"
+//
+// When unset, buildInfo falls back to the VCS stamp the go tool embeds.
+var (
+	version   = "dev"
+	gitCommit = ""
+	gitDirty  = ""
+)
+
+// buildInfo reports the ldflags values, filling an unset commit (and its dirty
+// flag) from the binary's embedded VCS settings.
+func buildInfo() api.BuildInfo {
+	b := api.BuildInfo{Version: version, Commit: gitCommit, Dirty: gitDirty == "true"}
+	if b.Commit != "" {
+		return b
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				b.Commit = s.Value
+			case "vcs.modified":
+				b.Dirty = s.Value == "true"
+			}
+		}
+	}
+	if b.Commit == "" {
+		b.Commit = "unknown"
+	}
+	return b
+}
+
+func versionString(b api.BuildInfo) string {
+	s := fmt.Sprintf("stock-screener %s (commit %s", b.Version, b.Commit)
+	if b.Dirty {
+		s += ", dirty"
+	}
+	return s + ")"
+}
+
+// openFeed returns the configured market-data client (collector.source) with
+// the collector's retry limits.
+func openFeed(cfg *config.Config) (collector.Fetcher, error) {
+	return datasource.New(cfg.Collector.Source, datasource.Options{
+		RetryBudget:  time.Duration(cfg.Collector.RetryBudget),
+		MaxRetryWait: time.Duration(cfg.Collector.MaxRetryWait),
+	})
+}
+
 func main() { os.Exit(run(os.Args)) }
 
 // run wires everything and returns a process exit code (0 ok, 1 runtime error,
@@ -56,16 +129,24 @@ func main() { os.Exit(run(os.Args)) }
 // running, unlike os.Exit inside main.
 func run(args []string) int {
 	if len(args) < 2 {
-		log.Printf("usage: %s <serve|collect> [--config config.yaml]", args[0])
+		log.Printf("usage: %s <serve|collect|health|download|version> [--config config.yaml]", args[0])
 		return 2
 	}
 	cmd := args[1]
-	if cmd != "serve" && cmd != "collect" {
-		log.Printf("unknown command %q (want serve|collect)", cmd)
+	if cmd == "version" || cmd == "--version" {
+		fmt.Println(versionString(buildInfo()))
+		return 0
+	}
+	if cmd != "serve" && cmd != "collect" && cmd != "health" && cmd != "download" {
+		log.Printf("unknown command %q (want serve|collect|health|download|version)", cmd)
 		return 2
 	}
 	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
 	cfgPath := fs.String("config", "config.yaml", "path to config file")
+	var dl *downloadFlags
+	if cmd == "download" {
+		dl = newDownloadFlags(fs)
+	}
 	_ = fs.Parse(args[2:])
 
 	cfg, err := config.Load(*cfgPath)
@@ -73,21 +154,27 @@ func run(args []string) int {
 		log.Printf("config: %v", err)
 		return 1
 	}
-	dsn, err := dsnFromEnv()
+	src, err := openFeed(cfg)
 	if err != nil {
-		log.Printf("db env: %v", err)
+		log.Printf("%v", err)
 		return 1
 	}
-	store, err := storage.NewPostgresStore(dsn)
+	switch cmd {
+	case "health":
+		return runChecks(context.Background(), healthChecks(cfg, src), healthCheckTimeout)
+	case "download":
+		return runDownload(src, dl, os.Stdout)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := openStore(ctx, cfg)
 	if err != nil {
-		log.Printf("db connect: %v", err)
+		log.Printf("%v", err)
 		return 1
 	}
 	defer store.Close()
 
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-
 	if err := store.Migrate(ctx); err != nil {
 		log.Printf("migrate: %v", err)
 		return 1
@@ -95,7 +182,7 @@ func run(args []string) int {
 
 	switch cmd {
 	case "collect":
-		errs := collector.New(store, yahoo.New(), cfg).CollectOnce(ctx)
+		errs := collector.New(store, src, cfg).CollectOnce(ctx)
 		for _, e := range errs {
 			log.Printf("collect error: %v", e)
 		}
@@ -106,28 +193,172 @@ func run(args []string) int {
 		log.Printf("collect finished: ok")
 		return 0
 	case "serve":
-		return serve(ctx, cfg, store)
+		return serve(ctx, cfg, store, src)
+	}
+	return 0
+}
+
+// healthCheckTimeout bounds each dependency probe of the health command.
+const healthCheckTimeout = 10 * time.Second
+
+// check is one dependency probe run by the health command.
+type check struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// healthChecks probes the dependencies serve and collect need — the store and
+// the market-data feed — directly, so a container healthcheck doesn't need the
+// HTTP server.
+func healthChecks(cfg *config.Config, src collector.Fetcher) []check {
+	feed := cfg.Collector.Source
+	if feed == "" {
+		feed = datasource.Yahoo
+	}
+	return []check{
+		{"db", func(ctx context.Context) error {
+			store, err := openStore(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			return store.Ping(ctx)
+		}},
+		{feed, func(ctx context.Context) error {
+			_, err := src.Fetch(ctx, cfg.Stocks[0], "1d", time.Now().AddDate(0, 0, -7))
+			return err
+		}},
+	}
+}
+
+// runChecks runs every check (each bounded by timeout), logs one result line
+// per component, and returns 0 if all passed or 1 otherwise. All checks run
+// even after a failure so the output names every broken dependency.
+func runChecks(ctx context.Context, checks []check, timeout time.Duration) int {
+	code := 0
+	for _, c := range checks {
+		cctx, cancel := context.WithTimeout(ctx, timeout)
+		err := c.run(cctx)
+		cancel()
+		if err != nil {
+			log.Printf("health %s: FAIL: %v", c.name, err)
+			code = 1
+			continue
+		}
+		log.Printf("health %s: ok", c.name)
+	}
+	return code
+}
+
+// downloadFlags are the download command's flags.
+type downloadFlags struct {
+	symbol, timeframe, from, to *string
+}
+
+func newDownloadFlags(fs *flag.FlagSet) *downloadFlags {
+	return &downloadFlags{
+		symbol:    fs.String("symbol", "", "symbol to download (required)"),
+		timeframe: fs.String("timeframe", "1d", "native timeframe (15m|30m|1h|1d|1wk|1mo)"),
+		from:      fs.String("from", "", "first day, YYYY-MM-DD (default 30 days ago)"),
+		to:        fs.String("to", "", "day to stop before, YYYY-MM-DD (default now)"),
+	}
+}
+
+// runDownload validates the download flags and writes the candles to w,
+// returning a process exit code (2 for bad flags).
+func runDownload(src collector.Fetcher, f *downloadFlags, w io.Writer) int {
+	if *f.symbol == "" {
+		log.Printf("download: --symbol is required")
+		return 2
+	}
+	from := time.Now().UTC().AddDate(0, 0, -30)
+	var to time.Time
+	for _, d := range []struct {
+		flag string
+		dst  *time.Time
+	}{{*f.from, &from}, {*f.to, &to}} {
+		if d.flag == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, d.flag)
+		if err != nil {
+			log.Printf("download: bad date %q (want YYYY-MM-DD)", d.flag)
+			return 2
+		}
+		*d.dst = t
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := download(ctx, src, w, *f.symbol, *f.timeframe, from, to); err != nil {
+		log.Printf("download: %v", err)
+		return 1
 	}
 	return 0
 }
 
-func serve(ctx context.Context, cfg *config.Config, store *storage.PostgresStore) int {
+// download writes symbol's candles from from (until before to, if set) as CSV,
+// whichever feed src is. Only native timeframes can be downloaded; derived
+// ones exist only as resampled bars.
+func download(ctx context.Context, src collector.Fetcher, w io.Writer, symbol, tfName string, from, to time.Time) error {
+	tf, ok := timeframe.Get(tfName)
+	if !ok || !tf.Native {
+		return fmt.Errorf("timeframe %q is not a native feed timeframe", tfName)
+	}
+	candles, err := src.Fetch(ctx, symbol, tf.YahooInterval, from)
+	if err != nil {
+		return fmt.Errorf("fetch %s %s: %w", symbol, tfName, err)
+	}
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"time", "open", "high", "low", "close", "volume"})
+	for _, c := range candles {
+		if !to.IsZero() && !c.Time.Before(to) {
+			continue
+		}
+		_ = cw.Write([]string{c.Time.UTC().Format(time.RFC3339), num(c.Open), num(c.High), num(c.Low), num(c.Close), num(c.Volume)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func serve(ctx context.Context, cfg *config.Config, store storage.Store, src collector.Fetcher) int {
 	var worker func(context.Context)
 	if cfg.Collector.Enabled {
-		worker = collector.New(store, yahoo.New(), cfg).Run
+		worker = collector.New(store, src, cfg).Run
 	}
 	scr := screener.New(store, cfg)
 	srv := api.NewServer(scr, store, cfg)
-	httpSrv := &http.Server{
+	build := buildInfo()
+	srv.SetBuildInfo(build)
+	httpSrv := newHTTPServer(cfg, srv.Handler())
+	log.Printf("%s listening on %s", versionString(build), httpSrv.Addr)
+	return serveLoop(ctx, httpSrv, worker)
+}
+
+// newHTTPServer builds the API server with the configured connection limits,
+// falling back to safe defaults for any left unset so a slow or stalled client
+// (slowloris) can never hold a connection open indefinitely.
+func newHTTPServer(cfg *config.Config, h http.Handler) *http.Server {
+	maxHeader := cfg.Server.MaxHeaderBytes
+	if maxHeader <= 0 {
+		maxHeader = 64 << 10
+	}
+	return &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:           srv.Handler(),
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       15 * time.Second,
-		WriteTimeout:      30 * time.Second,
-		IdleTimeout:       60 * time.Second,
+		Handler:           h,
+		ReadHeaderTimeout: durationOr(cfg.Server.ReadHeaderTimeout, 5*time.Second),
+		ReadTimeout:       durationOr(cfg.Server.ReadTimeout, 15*time.Second),
+		WriteTimeout:      durationOr(cfg.Server.WriteTimeout, 30*time.Second),
+		IdleTimeout:       durationOr(cfg.Server.IdleTimeout, 60*time.Second),
+		MaxHeaderBytes:    maxHeader,
 	}
-	log.Printf("listening on %s", httpSrv.Addr)
-	return serveLoop(ctx, httpSrv, worker)
+}
+
+func durationOr(d config.Duration, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return time.Duration(d)
 }
 
 // httpServer is the slice of *http.Server that serveLoop needs (so tests can
@@ -1,12 +1,24 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/Ruscigno/stock-screener/internal/config"
+	"github.com/Ruscigno/stock-screener/internal/datasource/yahoo"
 )
 
 func TestDSNFromEnv(t *testing.T) {
@@ -92,6 +104,28 @@ func TestRunUsageErrors(t *testing.T) {
 	}
 }
 
+func TestRunRejectsUnknownCollectorSource(t *testing.T) {
+	valid, err := os.ReadFile("internal/config/testdata/valid.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := strings.Replace(string(valid), "collector:\n", "collector:\n  source: mexc\n", 1)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	// The feed is resolved before the store is opened, so no DB env is needed.
+	if code := run([]string{"prog", "collect", "--config", path}); code != 1 {
+		t.Errorf("code = %d, want 1 for unknown collector.source", code)
+	}
+	if !strings.Contains(logs.String(), `unknown data source "mexc"`) {
+		t.Errorf("log = %q, want the unknown data source error", logs.String())
+	}
+}
+
 // fakeHTTPServer lets serveLoop be tested without binding a real port.
 type fakeHTTPServer struct {
 	started  chan struct{}
@@ -184,3 +218,238 @@ var errServe = serveErr("listen: address already in use")
 type serveErr string
 
 func (e serveErr) Error() string { return string(e) }
+
+func TestRunChecksAllPass(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	if code := runChecks(context.Background(), []check{{"db", ok}, {"yahoo", ok}}, time.Second); code != 0 {
+		t.Errorf("code = %d, want 0", code)
+	}
+}
+
+func TestRunChecksReportsEveryFailure(t *testing.T) {
+	var ran []string
+	mk := func(name string, err error) check {
+		return check{name, func(context.Context) error { ran = append(ran, name); return err }}
+	}
+	checks := []check{mk("db", errServe), mk("yahoo", nil), mk("other", errServe)}
+	if code := runChecks(context.Background(), checks, time.Second); code != 1 {
+		t.Errorf("code = %d, want 1 on component failure", code)
+	}
+	if len(ran) != 3 {
+		t.Errorf("ran %v, want all 3 checks despite the first failing", ran)
+	}
+}
+
+func TestRunChecksTimesOutHungComponent(t *testing.T) {
+	hung := check{"db", func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() }}
+	start := time.Now()
+	if code := runChecks(context.Background(), []check{hung}, 20*time.Millisecond); code != 1 {
+		t.Errorf("code = %d, want 1 for a hung component", code)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %v, want the check cut off at its timeout", elapsed)
+	}
+}
+
+func TestNewHTTPServerDefaultsAndOverrides(t *testing.T) {
+	cfg := &config.Config{}
+	srv := newHTTPServer(cfg, http.NotFoundHandler())
+	if srv.ReadHeaderTimeout != 5*time.Second || srv.ReadTimeout != 15*time.Second ||
+		srv.WriteTimeout != 30*time.Second || srv.IdleTimeout != 60*time.Second {
+		t.Errorf("defaults = %v/%v/%v/%v", srv.ReadHeaderTimeout, srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+	if srv.MaxHeaderBytes != 64<<10 {
+		t.Errorf("MaxHeaderBytes = %d, want %d", srv.MaxHeaderBytes, 64<<10)
+	}
+
+	cfg.Server.ReadHeaderTimeout = config.Duration(2 * time.Second)
+	cfg.Server.MaxHeaderBytes = 4096
+	srv = newHTTPServer(cfg, http.NotFoundHandler())
+	if srv.ReadHeaderTimeout != 2*time.Second || srv.MaxHeaderBytes != 4096 {
+		t.Errorf("overrides = %v/%d, want 2s/4096", srv.ReadHeaderTimeout, srv.MaxHeaderBytes)
+	}
+}
+
+func TestHTTPServerDropsSlowHeaderClient(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Server.ReadHeaderTimeout = config.Duration(50 * time.Millisecond)
+	srv := newHTTPServer(cfg, http.NotFoundHandler())
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	// Send an incomplete header block and then stall.
+	if _, err := conn.Write([]byte("GET /healthz HTTP/1.1\r\nHost: x\r\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	start := time.Now()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := io.ReadAll(conn); err != nil {
+		t.Fatalf("connection not closed by server: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("server closed after %v, want near the 50ms header timeout", elapsed)
+	}
+}
+
+func TestOpenStoreMemoryNeedsNoDBEnv(t *testing.T) {
+	t.Setenv("DB_USER", "")
+	cfg := &config.Config{}
+	cfg.Database.Backend = "memory"
+	store, err := openStore(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("openStore(memory): %v", err)
+	}
+	defer store.Close()
+	if err := store.Ping(context.Background()); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+
+	cfg.Database.Backend = ""
+	if _, err := openStore(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "db env") {
+		t.Errorf("openStore(postgres) without env: err = %v, want db env error", err)
+	}
+}
+
+func TestVersionIncludesLdflagsCommit(t *testing.T) {
+	oldVersion, oldCommit, oldDirty := version, gitCommit, gitDirty
+	defer func() { version, gitCommit, gitDirty = oldVersion, oldCommit, oldDirty }()
+	version, gitCommit, gitDirty = "v1.2.0", "abc1234", "true"
+
+	b := buildInfo()
+	if b.Commit != "abc1234" || !b.Dirty {
+		t.Fatalf("buildInfo = %+v, want commit abc1234, dirty", b)
+	}
+	if got, want := versionString(b), "stock-screener v1.2.0 (commit abc1234, dirty)"; got != want {
+		t.Errorf("versionString = %q, want %q", got, want)
+	}
+
+	gitDirty = ""
+	if got := versionString(buildInfo()); got != "stock-screener v1.2.0 (commit abc1234)" {
+		t.Errorf("clean versionString = %q", got)
+	}
+}
+
+type fakeFetcher struct {
+	candles  []yahoo.Candle
+	err      error
+	interval string
+	from     time.Time
+}
+
+func (f *fakeFetcher) Fetch(_ context.Context, _, interval string, from time.Time) ([]yahoo.Candle, error) {
+	f.interval, f.from = interval, from
+	return f.candles, f.err
+}
+
+func TestDownloadWritesCSVUntilTo(t *testing.T) {
+	t0 := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	src := &fakeFetcher{candles: []yahoo.Candle{
+		{Time: t0, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 100},
+		{Time: t0.AddDate(0, 0, 1), Open: 1.5, High: 3, Low: 1, Close: 2.5, Volume: 200},
+		{Time: t0.AddDate(0, 0, 2), Close: 9},
+	}}
+	var out bytes.Buffer
+	if err := download(context.Background(), src, &out, "AAPL", "1h", t0, t0.AddDate(0, 0, 2)); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if src.interval != "60m" || !src.from.Equal(t0) {
+		t.Errorf("fetched interval %q from %v, want 60m from %v", src.interval, src.from, t0)
+	}
+	want := "time,open,high,low,close,volume\n" +
+		"2026-06-01T00:00:00Z,1,2,0.5,1.5,100\n" +
+		"2026-06-02T00:00:00Z,1.5,3,1,2.5,200\n"
+	if out.String() != want {
+		t.Errorf("csv =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestDownloadErrors(t *testing.T) {
+	ctx := context.Background()
+	if err := download(ctx, &fakeFetcher{}, io.Discard, "AAPL", "4h", time.Now(), time.Time{}); err == nil {
+		t.Error("derived timeframe 4h: want error")
+	}
+	boom := errors.New("boom")
+	err := download(ctx, &fakeFetcher{err: boom}, io.Discard, "AAPL", "1d", time.Now(), time.Time{})
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "fetch AAPL 1d") {
+		t.Errorf("fetch error = %v, want wrapped boom", err)
+	}
+}
+
+func TestRunDownloadValidatesFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{},                                     // missing symbol
+		{"--symbol", "AAPL", "--from", "june"}, // bad date
+	} {
+		fs := flag.NewFlagSet("download", flag.ContinueOnError)
+		f := newDownloadFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("parse %v: %v", args, err)
+		}
+		if code := runDownload(&fakeFetcher{}, f, io.Discard); code != 2 {
+			t.Errorf("%v: code = %d, want 2", args, code)
+		}
+	}
+}
+
+func TestHealthDBCheckTimesOutOnHungDatabase(t *testing.T) {
+	// A listener that accepts but never answers the Postgres handshake.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	var mu sync.Mutex
+	var held []net.Conn
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, c := range held {
+			c.Close()
+		}
+	}()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			held = append(held, c)
+			mu.Unlock()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	t.Setenv("DB_USER", "u")
+	t.Setenv("DB_PASSWORD", "p")
+	t.Setenv("DB_HOST", host)
+	t.Setenv("DB_PORT", port)
+	t.Setenv("DB_NAME", "n")
+	t.Setenv("DB_SSLMODE", "disable")
+
+	cfg := &config.Config{}
+	cfg.Stocks = []string{"AAPL"}
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	start := time.Now()
+	code := runChecks(context.Background(), healthChecks(cfg, &fakeFetcher{}), 200*time.Millisecond)
+	if code != 1 {
+		t.Errorf("code = %d, want 1", code)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("health took %v, want the db probe cut off near the 200ms timeout", elapsed)
+	}
+	if !strings.Contains(logs.String(), "health db: FAIL") || !strings.Contains(logs.String(), "health yahoo: ok") {
+		t.Errorf("log = %q, want a db FAIL line and a yahoo ok line", logs.String())
+	}
+}